@@ -0,0 +1,32 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodehealth
+
+import "context"
+
+// Probe checks one dependency the node relies on to be ready (an L1 RPC
+// endpoint, the sequencer feed, the inbox reader, txdb, or a forwarder
+// target). A non-nil error from Check means the probe is failing and
+// readiness should be withheld.
+type Probe interface {
+	// Name identifies the probe in failure output, e.g. "l1-rpc" or
+	// "inbox-reader-lag".
+	Name() string
+
+	// Check returns nil if the dependency is healthy.
+	Check(ctx context.Context) error
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodehealth
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/dataavailability"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/ethclient"
+)
+
+// L1RPCProbe checks that the configured L1 endpoint is reachable, not
+// still syncing, and has produced a block recently. Client is the
+// failover-aware L1 client so a single degraded endpoint doesn't flip the
+// probe unhealthy while a backup is still serving fine.
+type L1RPCProbe struct {
+	Client      *ethclient.Client
+	MaxBlockAge time.Duration
+}
+
+func (p *L1RPCProbe) Name() string { return "l1-rpc" }
+
+func (p *L1RPCProbe) Check(ctx context.Context) error {
+	progress, err := p.Client.SyncProgress(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error calling eth_syncing")
+	}
+	if progress != nil {
+		return errors.Errorf("L1 node still syncing: %d/%d", progress.CurrentBlock, progress.HighestBlock)
+	}
+
+	if p.MaxBlockAge <= 0 {
+		return nil
+	}
+	header, err := p.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error fetching latest L1 header")
+	}
+	age := time.Since(time.Unix(int64(header.Time), 0))
+	if age > p.MaxBlockAge {
+		return errors.Errorf("latest L1 block is %s old, exceeds %s", age, p.MaxBlockAge)
+	}
+	return nil
+}
+
+// LagProbe is a generic "distance from head exceeds a threshold" probe,
+// reused for the sequencer feed, the inbox reader and txdb.
+type LagProbe struct {
+	ProbeName string
+	// CurrentLag returns how far behind this dependency currently is, in
+	// the unit appropriate to it (seconds for the feed, blocks for the
+	// inbox reader and txdb).
+	CurrentLag func() (int64, error)
+	MaxLag     int64
+}
+
+func (p *LagProbe) Name() string { return p.ProbeName }
+
+func (p *LagProbe) Check(ctx context.Context) error {
+	lag, err := p.CurrentLag()
+	if err != nil {
+		return errors.Wrapf(err, "error computing lag for %s", p.ProbeName)
+	}
+	if lag > p.MaxLag {
+		return errors.Errorf("%s is %d behind, exceeds limit %d", p.ProbeName, lag, p.MaxLag)
+	}
+	return nil
+}
+
+// ForwarderProbe checks that the forwarder's upstream target is reachable.
+type ForwarderProbe struct {
+	TargetURL string
+	Client    *http.Client
+}
+
+func (p *ForwarderProbe) Name() string { return "forwarder-upstream" }
+
+func (p *ForwarderProbe) Check(ctx context.Context) error {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TargetURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "error building forwarder healthcheck request")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error reaching forwarder target")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return errors.Errorf("forwarder target returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// daProbePayload is stored and re-fetched on every check so a DA backend
+// that's reachable but silently serving stale or corrupt data is caught,
+// not just a dead connection.
+var daProbePayload = []byte("arb-node healthcheck")
+
+// DataAvailabilityProbe checks that the sequencer's configured DA backend
+// is actually storing and serving back what's written to it, by round
+// tripping a small payload through Store and Retrieve on every check.
+type DataAvailabilityProbe struct {
+	DA dataavailability.DataAvailability
+}
+
+func (p *DataAvailabilityProbe) Name() string { return "data-availability" }
+
+func (p *DataAvailabilityProbe) Check(ctx context.Context) error {
+	commitment, _, err := p.DA.Store(ctx, daProbePayload)
+	if err != nil {
+		return errors.Wrap(err, "error storing healthcheck payload with DA backend")
+	}
+
+	payload, err := p.DA.Retrieve(ctx, commitment)
+	if err != nil {
+		return errors.Wrap(err, "error retrieving healthcheck payload from DA backend")
+	}
+	if !bytes.Equal(payload, daProbePayload) {
+		return errors.New("DA backend returned a different payload than was stored")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package nodehealth serves /livez, /readyz and /healthz endpoints backed
+// by pluggable Probe implementations, so load balancers can drain a node
+// deterministically when one of its dependencies is unhealthy.
+package nodehealth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+)
+
+var logger = log.With().Str("component", "nodehealth").Logger()
+
+// Server aggregates readiness Probes and serves them over HTTP.
+type Server struct {
+	// readyProbes must all pass for /readyz and /healthz to report ready;
+	// they represent "fully caught up and serving".
+	readyProbes []Probe
+
+	failureGauges map[string]gethmetrics.Gauge
+	registry      gethmetrics.Registry
+}
+
+// NewServer constructs a Server with no probes registered. Use Register to
+// add probes before calling Start.
+func NewServer(registry gethmetrics.Registry) *Server {
+	return &Server{
+		registry:      registry,
+		failureGauges: make(map[string]gethmetrics.Gauge),
+	}
+}
+
+// Register adds probe to the set checked by /readyz and /healthz.
+func (s *Server) Register(probe Probe) {
+	s.readyProbes = append(s.readyProbes, probe)
+	if s.registry != nil {
+		s.failureGauges[probe.Name()] = gethmetrics.NewRegisteredGauge("arb/nodehealth/failing/"+probe.Name(), s.registry)
+	}
+}
+
+type probeFailure struct {
+	Component string `json:"component"`
+	Error     string `json:"error"`
+}
+
+type statusResponse struct {
+	Status   string         `json:"status"`
+	Failures []probeFailure `json:"failures,omitempty"`
+}
+
+func (s *Server) checkReady(ctx context.Context) []probeFailure {
+	var failures []probeFailure
+	for _, probe := range s.readyProbes {
+		err := probe.Check(ctx)
+		gauge := s.failureGauges[probe.Name()]
+		if err != nil {
+			if gauge != nil {
+				gauge.Update(1)
+			}
+			failures = append(failures, probeFailure{Component: probe.Name(), Error: err.Error()})
+			continue
+		}
+		if gauge != nil {
+			gauge.Update(0)
+		}
+	}
+	return failures
+}
+
+func writeStatus(w http.ResponseWriter, failures []probeFailure) {
+	resp := statusResponse{Status: "ok"}
+	code := http.StatusOK
+	if len(failures) > 0 {
+		resp.Status = "unavailable"
+		resp.Failures = failures
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Warn().Err(err).Msg("error encoding healthcheck response")
+	}
+}
+
+// Handler builds the mux serving /livez, /readyz and /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	// /livez only confirms the process is up and able to respond; it never
+	// consults probes, so a dependency outage doesn't get the process
+	// killed by an orchestrator's liveness check.
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, nil)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, s.checkReady(r.Context()))
+	})
+
+	// /healthz aggregates the same probes as /readyz today; kept as a
+	// separate endpoint so the two can diverge later (e.g. readyz could
+	// drop out of a load balancer earlier than healthz alerts an operator).
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, s.checkReady(r.Context()))
+	})
+
+	return mux
+}
+
+// Start serves the health endpoints on addr until ctx is cancelled.
+func (s *Server) Start(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: s.Handler(),
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return srv.Close()
+	case err := <-errChan:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourcemanager
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-node-core/metrics"
+)
+
+// refreshInterval is how often a cgroupsLimitChecker re-reads memory usage
+// from cgroup/proc files in the background. IsLimitExceeded and FreeMemory
+// only ever read the cached result of the last refresh, so they stay cheap
+// enough to call from hot paths like the RPC request middleware.
+const refreshInterval = 5 * time.Second
+
+const (
+	cgroupV2MemCurrent = "/sys/fs/cgroup/memory.current"
+	cgroupV2MemMax     = "/sys/fs/cgroup/memory.max"
+	cgroupV1MemUsage   = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
+	cgroupV1MemLimit   = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+	procMeminfo        = "/proc/meminfo"
+)
+
+// cgroupsMemReader reads the memory actually available to this process,
+// taking the tighter of the host's MemAvailable and the container's cgroup
+// limit (if any).
+type cgroupsMemReader struct {
+	usagePath      string
+	limitPath      string
+	cgroupsEnabled bool
+}
+
+func newCgroupsMemReader() (*cgroupsMemReader, error) {
+	if _, err := os.Stat(cgroupV2MemCurrent); err == nil {
+		return &cgroupsMemReader{usagePath: cgroupV2MemCurrent, limitPath: cgroupV2MemMax, cgroupsEnabled: true}, nil
+	}
+	if _, err := os.Stat(cgroupV1MemUsage); err == nil {
+		return &cgroupsMemReader{usagePath: cgroupV1MemUsage, limitPath: cgroupV1MemLimit, cgroupsEnabled: true}, nil
+	}
+	// No cgroup memory controller available (e.g. running outside a
+	// container); fall back to /proc/meminfo only.
+	return &cgroupsMemReader{cgroupsEnabled: false}, nil
+}
+
+// freeBytes returns the number of bytes of memory still available to this
+// process before it would be expected to hit its limit.
+func (r *cgroupsMemReader) freeBytes() (uint64, error) {
+	memAvailable, err := readMemAvailable()
+	if err != nil {
+		return 0, errors.Wrap(err, "error reading /proc/meminfo")
+	}
+
+	if !r.cgroupsEnabled {
+		return memAvailable, nil
+	}
+
+	usage, err := readUintFile(r.usagePath)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error reading %s", r.usagePath)
+	}
+
+	limit, err := readUintFile(r.limitPath)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error reading %s", r.limitPath)
+	}
+
+	// "max" in cgroup v2 (or an implausibly large v1 value) means the
+	// container has no memory limit of its own; defer to the host figure.
+	if limit == 0 || limit > usage+memAvailable {
+		return memAvailable, nil
+	}
+
+	cgroupFree := limit - usage
+	if cgroupFree < memAvailable {
+		return cgroupFree, nil
+	}
+	return memAvailable, nil
+}
+
+func readUintFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func readMemAvailable() (uint64, error) {
+	f, err := os.Open(procMeminfo)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "MemAvailable:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0, errors.Errorf("unexpected MemAvailable line %q", line)
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "unexpected MemAvailable value %q", line)
+		}
+		return kb * 1024, nil
+	}
+	return 0, errors.New("MemAvailable not found in /proc/meminfo")
+}
+
+// cgroupsLimitChecker is the LimitChecker implementation backed by cgroups
+// and /proc/meminfo. Memory usage is only actually read by run, on a
+// timer; IsLimitExceeded and FreeMemory just report the cached result of
+// the last reading.
+type cgroupsLimitChecker struct {
+	limit  uint64
+	reader *cgroupsMemReader
+
+	freeMemory   uint64 // atomic, bytes
+	exceeded     uint32 // atomic, 0 or 1
+	pauseCounter gethmetrics.Counter
+	freeGauge    gethmetrics.Gauge
+}
+
+func (c *cgroupsLimitChecker) registerMetrics(metricsConfig *metrics.MetricsConfig) {
+	c.pauseCounter = gethmetrics.NewRegisteredCounter("arb/resourcemanager/pauses", metricsConfig.Registry)
+	c.freeGauge = gethmetrics.NewRegisteredGauge("arb/resourcemanager/freememory", metricsConfig.Registry)
+}
+
+// refresh re-reads memory usage and updates the cached free memory and
+// exceeded flag that IsLimitExceeded/FreeMemory report.
+func (c *cgroupsLimitChecker) refresh() {
+	free, err := c.reader.freeBytes()
+	if err != nil {
+		logger.Warn().Err(err).Msg("error reading memory usage, assuming no memory pressure")
+		atomic.StoreUint32(&c.exceeded, 0)
+		return
+	}
+
+	atomic.StoreUint64(&c.freeMemory, free)
+	if c.freeGauge != nil {
+		c.freeGauge.Update(int64(free))
+	}
+
+	var exceededFlag uint32
+	if free < c.limit {
+		exceededFlag = 1
+		if c.pauseCounter != nil {
+			c.pauseCounter.Inc(1)
+		}
+	}
+	atomic.StoreUint32(&c.exceeded, exceededFlag)
+}
+
+// run refreshes the cached memory reading on a timer until ctx is
+// cancelled.
+func (c *cgroupsLimitChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh()
+		}
+	}
+}
+
+func (c *cgroupsLimitChecker) IsLimitExceeded() bool {
+	return atomic.LoadUint32(&c.exceeded) == 1
+}
+
+func (c *cgroupsLimitChecker) FreeMemory() uint64 {
+	return atomic.LoadUint64(&c.freeMemory)
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourcemanager
+
+import "net/http"
+
+// WrapHTTPHandler wraps handler so that, while checker reports memory
+// pressure, incoming requests are rejected with 429 Too Many Requests
+// instead of being allowed to make things worse. checker.IsLimitExceeded
+// is backed by a cache refreshed on a timer (see cgroupsLimitChecker), so
+// this adds no per-request filesystem reads even under heavy load.
+func WrapHTTPHandler(checker LimitChecker, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if checker.IsLimitExceeded() {
+			http.Error(w, "node is low on memory, try again later", http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourcemanager
+
+import (
+	"context"
+	"time"
+)
+
+// Pausable is implemented by a long running background loop - the inbox
+// reader's optional validation pass, the batcher's posting loop - that can
+// suspend its non-essential work under memory pressure and resume once it
+// recovers.
+type Pausable interface {
+	// SetPaused is called with true when the node is low on memory and
+	// should defer this target's non-essential work, and with false once
+	// memory has recovered.
+	SetPaused(paused bool)
+}
+
+// pollInterval is how often Monitor re-checks the LimitChecker.
+const pollInterval = 5 * time.Second
+
+// Monitor polls checker on a fixed interval and propagates pause/resume
+// transitions to every target, so non-essential background work backs off
+// while the node is under memory pressure instead of only throttling
+// incoming RPC requests. It runs until ctx is cancelled.
+func Monitor(ctx context.Context, checker LimitChecker, targets ...Pausable) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	paused := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			exceeded := checker.IsLimitExceeded()
+			if exceeded == paused {
+				continue
+			}
+			paused = exceeded
+			if paused {
+				logger.Warn().Msg("memory pressure detected, pausing non-essential background work")
+			} else {
+				logger.Info().Msg("memory pressure resolved, resuming normal operation")
+			}
+			for _, target := range targets {
+				target.SetPaused(paused)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package resourcemanager protects the node from OOM under load by tracking
+// available system memory and letting callers cheaply check whether they
+// should back off non-essential work.
+package resourcemanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-node-core/metrics"
+)
+
+var logger = log.With().Str("component", "resourcemanager").Logger()
+
+// LimitChecker reports whether the node is currently under memory pressure.
+// Implementations are expected to be cheap to call repeatedly from hot paths
+// such as the RPC request handler and the inbox reader / batcher loops.
+type LimitChecker interface {
+	// IsLimitExceeded returns true if free memory has dipped below the
+	// configured limit and non-essential work should be paused.
+	IsLimitExceeded() bool
+
+	// FreeMemory returns the current amount of available memory in bytes,
+	// as last observed by the checker.
+	FreeMemory() uint64
+}
+
+// nullLimitChecker is used when no MemFreeLimit is configured; it never
+// reports memory pressure.
+type nullLimitChecker struct{}
+
+func (nullLimitChecker) IsLimitExceeded() bool { return false }
+func (nullLimitChecker) FreeMemory() uint64    { return 0 }
+
+// ParseMemLimit parses a human readable memory size such as "1G", "512M" or
+// "2048K" into a number of bytes. A bare number is interpreted as bytes. An
+// empty string returns 0 with no error, meaning "no limit configured".
+func ParseMemLimit(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	suffixes := map[byte]uint64{
+		'K': 1 << 10,
+		'M': 1 << 20,
+		'G': 1 << 30,
+		'T': 1 << 40,
+	}
+
+	last := s[len(s)-1]
+	numPart := s
+	multiplier := uint64(1)
+	if mult, ok := suffixes[strings.ToUpper(string(last))[0]]; ok {
+		multiplier = mult
+		numPart = s[:len(s)-1]
+	}
+
+	val, err := strconv.ParseUint(strings.TrimSpace(numPart), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid mem-free-limit %q", s)
+	}
+
+	return val * multiplier, nil
+}
+
+// New constructs a LimitChecker from a --node.rpc.mem-free-limit style
+// configuration string. An empty memFreeLimit disables the checker. The
+// returned checker reads current memory usage once synchronously, then
+// keeps it refreshed in the background until ctx is cancelled.
+func New(ctx context.Context, memFreeLimit string, metricsConfig *metrics.MetricsConfig) (LimitChecker, error) {
+	limit, err := ParseMemLimit(memFreeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		return nullLimitChecker{}, nil
+	}
+
+	reader, err := newCgroupsMemReader()
+	if err != nil {
+		return nil, errors.Wrap(err, "error detecting cgroups memory reader")
+	}
+
+	checker := &cgroupsLimitChecker{
+		limit:  limit,
+		reader: reader,
+	}
+	checker.registerMetrics(metricsConfig)
+	checker.refresh()
+	go checker.run(ctx)
+
+	logger.Info().Str("limit", fmt.Sprintf("%d", limit)).Msg("memory-aware RPC throttling enabled")
+
+	return checker, nil
+}
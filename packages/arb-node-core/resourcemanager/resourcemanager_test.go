@@ -0,0 +1,66 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourcemanager
+
+import "testing"
+
+func TestParseMemLimit(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    uint64
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "0", want: 0},
+		{input: "1024", want: 1024},
+		{input: "1K", want: 1 << 10},
+		{input: "512k", want: 512 << 10},
+		{input: "1M", want: 1 << 20},
+		{input: "2G", want: 2 << 30},
+		{input: "1T", want: 1 << 40},
+		{input: "  1G  ", want: 1 << 30},
+		{input: "notanumber", wantErr: true},
+		{input: "1X", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseMemLimit(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseMemLimit(%q): expected error, got none", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMemLimit(%q): unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseMemLimit(%q) = %d, want %d", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestNullLimitChecker(t *testing.T) {
+	var checker LimitChecker = nullLimitChecker{}
+	if checker.IsLimitExceeded() {
+		t.Error("nullLimitChecker should never report memory pressure")
+	}
+	if checker.FreeMemory() != 0 {
+		t.Error("nullLimitChecker.FreeMemory should be 0")
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// runAdminCommand implements the `arb-node admin <find-lca|remove-blocks>`
+// subcommands. They are thin JSON-RPC clients against a running node's own
+// admin_* namespace, used for txdb recovery after a bad reorg or corrupted
+// local state.
+func runAdminCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: arb-node admin <find-lca|remove-blocks> --rpc-url=<url> [args]")
+	}
+
+	switch args[0] {
+	case "find-lca":
+		return runAdminFindLCA(args[1:])
+	case "remove-blocks":
+		return runAdminRemoveBlocks(args[1:])
+	default:
+		return errors.Errorf("unknown admin subcommand %q", args[0])
+	}
+}
+
+func runAdminFindLCA(args []string) error {
+	fs := flag.NewFlagSet("admin find-lca", flag.ExitOnError)
+	rpcURL := fs.String("rpc-url", "http://localhost:8549", "arb-node admin RPC endpoint (--node.admin.addr/--node.admin.port)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := gethrpc.Dial(*rpcURL)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to arb-node")
+	}
+	defer client.Close()
+
+	var height uint64
+	if err := client.CallContext(context.Background(), &height, "admin_findLatestCommonAncestor"); err != nil {
+		return errors.Wrap(err, "error calling admin_findLatestCommonAncestor")
+	}
+
+	fmt.Printf("Latest common ancestor: block %d\n", height)
+	return nil
+}
+
+func runAdminRemoveBlocks(args []string) error {
+	fs := flag.NewFlagSet("admin remove-blocks", flag.ExitOnError)
+	rpcURL := fs.String("rpc-url", "http://localhost:8549", "arb-node admin RPC endpoint (--node.admin.addr/--node.admin.port)")
+	fromBlock := fs.Uint64("from-block", 0, "truncate all stored blocks with height >= this value")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fromBlock == 0 {
+		return errors.New("--from-block is required and must be > 0")
+	}
+
+	client, err := gethrpc.Dial(*rpcURL)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to arb-node")
+	}
+	defer client.Close()
+
+	if err := client.CallContext(context.Background(), nil, "admin_removeBlocks", *fromBlock); err != nil {
+		return errors.Wrap(err, "error calling admin_removeBlocks")
+	}
+
+	fmt.Printf("Removed blocks from height %d onward\n", *fromBlock)
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
 	"strings"
 	"time"
 
@@ -38,8 +39,10 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-node-core/metrics"
 	"github.com/offchainlabs/arbitrum/packages/arb-node-core/monitor"
 	"github.com/offchainlabs/arbitrum/packages/arb-node-core/nodehealth"
+	"github.com/offchainlabs/arbitrum/packages/arb-node-core/resourcemanager"
 	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/aggregator"
 	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/batcher"
+	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/dataavailability"
 	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/rpc"
 	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/txdb"
 	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/web3"
@@ -47,20 +50,27 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/broadcaster"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/configuration"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/ethclient"
 )
 
 var logger zerolog.Logger
 
 var pprofMux *http.ServeMux
 
-const largeChannelBuffer = 200
-
 func init() {
 	pprofMux = http.DefaultServeMux
 	http.DefaultServeMux = http.NewServeMux()
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := runAdminCommand(os.Args[2:]); err != nil {
+			fmt.Printf("%s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Enable line numbers in logging
 	golog.SetFlags(golog.LstdFlags | golog.Lshortfile)
 
@@ -83,14 +93,15 @@ func printSampleUsage() {
 	fmt.Printf("          or:  forwarder node: arb-node --l1.url=<L1 RPC> [optional arguments]\n\n")
 	fmt.Printf("          or: aggregator node: arb-node --l1.url=<L1 RPC> --node.type=aggregator [optional arguments] %s\n", cmdhelp.WalletArgsString)
 	fmt.Printf("          or:       sequencer: arb-node --l1.url=<L1 RPC> --node.type=sequencer [optional arguments] %s\n", cmdhelp.WalletArgsString)
+	fmt.Printf("          or:     admin tools: arb-node admin <find-lca|remove-blocks> --rpc-url=<node admin RPC>\n")
 }
 
 func startup() error {
 	ctx, cancelFunc, cancelChan := cmdhelp.CreateLaunchContext()
 	defer cancelFunc()
 
-	config, wallet, l1Client, l1ChainId, err := configuration.ParseNode(ctx)
-	if err != nil || len(config.Persistent.GlobalConfig) == 0 || len(config.L1.URL) == 0 ||
+	config, wallet, _, l1ChainId, err := configuration.ParseNode(ctx)
+	if err != nil || len(config.Persistent.GlobalConfig) == 0 || (len(config.L1.URL) == 0 && len(config.L1.URLs) == 0) ||
 		len(config.Rollup.Address) == 0 || len(config.BridgeUtilsAddress) == 0 ||
 		((config.Node.Type != "sequencer") && len(config.Node.Sequencer.Lockout.Redis) != 0) ||
 		((len(config.Node.Sequencer.Lockout.Redis) == 0) != (len(config.Node.Sequencer.Lockout.SelfRPCURL) == 0)) {
@@ -170,26 +181,31 @@ func startup() error {
 	defer mon.Close()
 
 	metricsConfig := metrics.NewMetricsConfig(&config.Healthcheck.MetricsPrefix)
-	healthChan := make(chan nodehealth.Log, largeChannelBuffer)
-	go func() {
-		err := nodehealth.StartNodeHealthCheck(ctx, healthChan, metricsConfig.Registry, metricsConfig.Registerer)
-		if err != nil {
-			log.Error().Err(err).Msg("healthcheck server failed")
-		}
-	}()
 
-	healthChan <- nodehealth.Log{Config: true, Var: "healthcheckMetrics", ValBool: config.Healthcheck.Metrics}
-	healthChan <- nodehealth.Log{Config: true, Var: "disablePrimaryCheck", ValBool: !config.Healthcheck.Sequencer}
-	healthChan <- nodehealth.Log{Config: true, Var: "disableOpenEthereumCheck", ValBool: !config.Healthcheck.L1Node}
-	healthChan <- nodehealth.Log{Config: true, Var: "healthcheckRPC", ValStr: config.Healthcheck.Addr + ":" + config.Healthcheck.Port}
+	l1URLs := config.L1.URLs
+	if l1URLs == "" {
+		l1URLs = config.L1.URL
+	}
+	l1Client, err := ethclient.DialFailover(ctx, l1URLs, metricsConfig.Registry)
+	if err != nil {
+		return errors.Wrap(err, "error dialing L1 endpoints")
+	}
 
+	limitChecker, err := resourcemanager.New(ctx, config.Node.RPC.MemFreeLimit, metricsConfig)
+	if err != nil {
+		return errors.Wrap(err, "error creating resourcemanager limit checker")
+	}
+
+	healthServer := nodehealth.NewServer(metricsConfig.Registry)
+	if config.Healthcheck.L1Node {
+		healthServer.Register(&nodehealth.L1RPCProbe{Client: l1Client, MaxBlockAge: time.Duration(config.Healthcheck.MaxL1BlockAgeSeconds) * time.Second})
+	}
 	if config.Node.Type == "forwarder" {
-		healthChan <- nodehealth.Log{Config: true, Var: "primaryHealthcheckRPC", ValStr: config.Node.Forwarder.Target}
+		healthServer.Register(&nodehealth.ForwarderProbe{TargetURL: config.Node.Forwarder.Target})
 	}
-	healthChan <- nodehealth.Log{Config: true, Var: "openethereumHealthcheckRPC", ValStr: config.L1.URL}
-	nodehealth.Init(healthChan)
 
 	var sequencerFeed chan broadcaster.BroadcastFeedMessage
+	var feedClients []*broadcastclient.BroadcastClient
 	if len(config.Feed.Input.URLs) == 0 {
 		logger.Warn().Msg("Missing --feed.url so not subscribing to feed")
 	} else {
@@ -197,11 +213,34 @@ func startup() error {
 		for _, url := range config.Feed.Input.URLs {
 			broadcastClient := broadcastclient.NewBroadcastClient(url, nil, config.Feed.Input.Timeout)
 			broadcastClient.ConnectInBackground(ctx, sequencerFeed)
+			feedClients = append(feedClients, broadcastClient)
 		}
+		healthServer.Register(&nodehealth.LagProbe{
+			ProbeName: "sequencer-feed-lag",
+			MaxLag:    config.Healthcheck.MaxFeedLagSeconds,
+			CurrentLag: func() (int64, error) {
+				var worst int64
+				for _, client := range feedClients {
+					lag := int64(time.Since(client.LastReceivedMessage()).Seconds())
+					if lag > worst {
+						worst = lag
+					}
+				}
+				return worst, nil
+			},
+		})
+	}
+	da, err := dataavailability.New(config.Node.Sequencer.DataAvailability)
+	if err != nil {
+		return errors.Wrap(err, "error setting up data availability backend")
+	}
+	if config.Node.Type == "sequencer" && dataavailability.Mode(config.Node.Sequencer.DataAvailability.Mode) == dataavailability.ExternalCommitteeMode {
+		healthServer.Register(&nodehealth.DataAvailabilityProbe{DA: da})
 	}
+
 	var inboxReader *monitor.InboxReader
 	for {
-		inboxReader, err = mon.StartInboxReader(ctx, l1Client, common.HexToAddress(config.Rollup.Address), config.Rollup.FromBlock, common.HexToAddress(config.BridgeUtilsAddress), healthChan, sequencerFeed)
+		inboxReader, err = mon.StartInboxReader(ctx, l1Client, common.HexToAddress(config.Rollup.Address), config.Rollup.FromBlock, common.HexToAddress(config.BridgeUtilsAddress), sequencerFeed, limitChecker, da)
 		if err == nil {
 			break
 		}
@@ -218,6 +257,12 @@ func startup() error {
 		}
 	}
 
+	healthServer.Register(&nodehealth.LagProbe{
+		ProbeName:  "inbox-reader-lag",
+		MaxLag:     config.Healthcheck.MaxInboxLagBlocks,
+		CurrentLag: inboxReader.BlocksBehindL1Tip,
+	})
+
 	var dataSigner func([]byte) ([]byte, error)
 	var batcherMode rpc.BatcherMode
 	if config.Node.Type == "forwarder" {
@@ -248,6 +293,7 @@ func startup() error {
 				InboxReader:                inboxReader,
 				DelayedMessagesTargetDelay: big.NewInt(config.Node.Sequencer.DelayedMessagesTargetDelay),
 				CreateBatchBlockInterval:   big.NewInt(config.Node.Sequencer.CreateBatchBlockInterval),
+				DataAvailability:           da,
 			}
 		} else {
 			inboxAddress := common.HexToAddress(config.Node.Aggregator.InboxAddress)
@@ -267,6 +313,19 @@ func startup() error {
 	}
 	defer db.Close()
 
+	healthServer.Register(&nodehealth.LagProbe{
+		ProbeName:  "txdb-catchup",
+		MaxLag:     config.Healthcheck.MaxTxDBLagBlocks,
+		CurrentLag: db.BlocksBehindNodeStore,
+	})
+
+	go func() {
+		addr := config.Healthcheck.Addr + ":" + config.Healthcheck.Port
+		if err := healthServer.Start(ctx, addr); err != nil {
+			log.Error().Err(err).Msg("healthcheck server failed")
+		}
+	}()
+
 	if config.WaitToCatchUp {
 		inboxReader.WaitToCatchUp(ctx)
 	}
@@ -284,6 +343,7 @@ func startup() error {
 			batcherMode,
 			dataSigner,
 			config,
+			limitChecker,
 		)
 		lockoutConf := config.Node.Sequencer.Lockout
 		if err == nil && lockoutConf.Redis != "" {
@@ -291,6 +351,7 @@ func startup() error {
 		}
 		if err == nil {
 			go batch.Start(ctx)
+			go resourcemanager.Monitor(ctx, limitChecker, inboxReader, batch)
 			break
 		}
 		logger.Warn().Err(err).Msg("failed to setup batcher, waiting and retrying")
@@ -310,8 +371,25 @@ func startup() error {
 	if err != nil {
 		return err
 	}
+
+	if config.Node.Admin.CanonicalRPCURL != "" {
+		canonicalSource, err := rpc.NewRPCCanonicalSource(ctx, config.Node.Admin.CanonicalRPCURL)
+		if err != nil {
+			return errors.Wrap(err, "error setting up admin API canonical source")
+		}
+		adminAPI := rpc.NewAdminAPI(nodeStore, canonicalSource, inboxReader)
+		go func() {
+			err := rpc.LaunchAdminServer(ctx, adminAPI, config.Node.Admin.Addr, config.Node.Admin.Port)
+			if err != nil {
+				errChan <- err
+			}
+		}()
+	}
+
+	throttledWeb3Server := resourcemanager.WrapHTTPHandler(limitChecker, web3Server)
+	wsConfig := rpc.NewWSConfig(config.Node.WS.MaxMessageSize, metricsConfig.Registry)
 	go func() {
-		err := rpc.LaunchPublicServer(ctx, web3Server, config.Node.RPC.Addr, config.Node.RPC.Port, config.Node.WS.Addr, config.Node.WS.Port)
+		err := rpc.LaunchPublicServer(ctx, throttledWeb3Server, config.Node.RPC.Addr, config.Node.RPC.Port, config.Node.WS.Addr, config.Node.WS.Port, wsConfig)
 		if err != nil {
 			errChan <- err
 		}
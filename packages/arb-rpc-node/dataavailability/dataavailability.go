@@ -0,0 +1,74 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dataavailability lets the sequencer post batch payloads somewhere
+// other than L1 calldata, with only a short commitment and signature set
+// carried on-chain in the inbox message.
+package dataavailability
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// DataAvailability stores and retrieves batch payloads for a sequencer
+// batch. Implementations may post the full payload on L1 (the default,
+// no-op "onchain" backend) or hand it off to an external committee / rollup
+// DA service, in which case only the returned commitment and proof travel
+// in the L1 inbox message.
+type DataAvailability interface {
+	// Store persists batchBytes with the backend and returns a short
+	// commitment identifying it plus a proof (e.g. a committee signature
+	// set) attesting to its availability.
+	Store(ctx context.Context, batchBytes []byte) (commitment []byte, proof []byte, err error)
+
+	// Retrieve fetches the batch payload previously stored under
+	// commitment.
+	Retrieve(ctx context.Context, commitment []byte) ([]byte, error)
+}
+
+// Mode selects which DataAvailability backend to construct.
+type Mode string
+
+const (
+	OnchainMode           Mode = "onchain"
+	ExternalCommitteeMode Mode = "external-committee"
+)
+
+// Config is parsed from --node.sequencer.data-availability.*.
+type Config struct {
+	Mode             string `koanf:"mode"`
+	CommitteeRPCURL  string `koanf:"committee-rpc-url"`
+	CommitteeTimeout int64  `koanf:"committee-timeout"`
+}
+
+// New constructs a DataAvailability backend from config. An empty or
+// "onchain" Mode returns the default backend that keeps posting full batch
+// payloads to L1 calldata.
+func New(config Config) (DataAvailability, error) {
+	switch Mode(config.Mode) {
+	case "", OnchainMode:
+		return &onchainDA{}, nil
+	case ExternalCommitteeMode:
+		if config.CommitteeRPCURL == "" {
+			return nil, errors.New("--node.sequencer.data-availability.committee-rpc-url is required for external-committee mode")
+		}
+		return newExternalCommitteeDA(config), nil
+	default:
+		return nil, errors.Errorf("unknown data availability mode %q", config.Mode)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dataavailability
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// externalCommitteeDA stores batch payloads with a third-party DA
+// committee over JSON-RPC, carrying only the returned commitment and
+// signature set on L1.
+type externalCommitteeDA struct {
+	dialMutex sync.Mutex
+	client    *rpc.Client
+	url       string
+	timeout   time.Duration
+}
+
+func newExternalCommitteeDA(config Config) *externalCommitteeDA {
+	timeout := time.Duration(config.CommitteeTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &externalCommitteeDA{
+		url:     config.CommitteeRPCURL,
+		timeout: timeout,
+	}
+}
+
+func (d *externalCommitteeDA) dial(ctx context.Context) (*rpc.Client, error) {
+	d.dialMutex.Lock()
+	defer d.dialMutex.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+	client, err := rpc.DialContext(ctx, d.url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error dialing DA committee at %s", d.url)
+	}
+	d.client = client
+	return client, nil
+}
+
+// committeeStoreResult mirrors the response of the committee's
+// das_store RPC method: a content commitment plus the set of member
+// signatures attesting to availability.
+type committeeStoreResult struct {
+	Commitment []byte `json:"commitment"`
+	Proof      []byte `json:"proof"`
+}
+
+func (d *externalCommitteeDA) Store(ctx context.Context, batchBytes []byte) ([]byte, []byte, error) {
+	client, err := d.dial(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var result committeeStoreResult
+	if err := client.CallContext(ctx, &result, "das_store", batchBytes); err != nil {
+		return nil, nil, errors.Wrap(err, "error storing batch with DA committee")
+	}
+	return result.Commitment, result.Proof, nil
+}
+
+func (d *externalCommitteeDA) Retrieve(ctx context.Context, commitment []byte) ([]byte, error) {
+	client, err := d.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var payload []byte
+	if err := client.CallContext(ctx, &payload, "das_retrieve", commitment); err != nil {
+		return nil, errors.Wrap(err, "error retrieving batch from DA committee")
+	}
+	return payload, nil
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dataavailability
+
+import "context"
+
+// onchainDA is the default DataAvailability backend: it does not move the
+// payload anywhere, so the full batch continues to be posted as L1
+// calldata by the caller. The "commitment" is simply the batch bytes
+// themselves, and there is no separate proof.
+type onchainDA struct{}
+
+func (*onchainDA) Store(_ context.Context, batchBytes []byte) ([]byte, []byte, error) {
+	return batchBytes, nil, nil
+}
+
+func (*onchainDA) Retrieve(_ context.Context, commitment []byte) ([]byte, error) {
+	return commitment, nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+var adminLogger = log.With().Str("component", "admin-rpc").Logger()
+
+// AdminNodeStore is the slice of mon.Storage.GetNodeStore() the admin API
+// needs to walk and truncate stored chain state.
+type AdminNodeStore interface {
+	// BlockHashAtHeight returns the hash this node has stored for height,
+	// if any.
+	BlockHashAtHeight(height uint64) (common.Hash, bool, error)
+
+	// LatestBlockHeight returns the height of the node's current head.
+	LatestBlockHeight() (uint64, error)
+
+	// TruncateFrom atomically removes all stored blocks, logs, receipts
+	// and derived indexes with height >= fromBlock.
+	TruncateFrom(fromBlock uint64) error
+}
+
+// AdminCanonicalSource resolves the block hash canonical chain state
+// considers correct at a given height, so FindLatestCommonAncestor can
+// detect where this node's local store diverged after a reorg.
+type AdminCanonicalSource interface {
+	CanonicalBlockHash(ctx context.Context, height uint64) (common.Hash, error)
+}
+
+// AdminInboxReader is the subset of monitor.InboxReader the admin API needs
+// to re-derive state after a RemoveBlocks call.
+type AdminInboxReader interface {
+	ResetToBlock(height uint64) error
+}
+
+// AdminAPI implements the "admin" JSON-RPC namespace used for txdb
+// disaster recovery: finding the last block this node's local store still
+// agrees with canonical chain state, and truncating everything after a bad
+// reorg or local corruption so the inbox reader can re-derive it from L1.
+type AdminAPI struct {
+	nodeStore   AdminNodeStore
+	canonical   AdminCanonicalSource
+	inboxReader AdminInboxReader
+}
+
+// NewAdminAPI constructs the admin namespace backed by nodeStore (from
+// mon.Storage.GetNodeStore()), canonical (the configured L2 RPC / L1 inbox
+// state used to validate against), and inboxReader (whose cursor is reset
+// after RemoveBlocks).
+func NewAdminAPI(nodeStore AdminNodeStore, canonical AdminCanonicalSource, inboxReader AdminInboxReader) *AdminAPI {
+	return &AdminAPI{
+		nodeStore:   nodeStore,
+		canonical:   canonical,
+		inboxReader: inboxReader,
+	}
+}
+
+// FindLatestCommonAncestor walks backward from this node's head comparing
+// stored block hashes against canonical chain state, returning the height
+// of the deepest block that still matches. It is exposed as
+// admin_findLatestCommonAncestor.
+func (a *AdminAPI) FindLatestCommonAncestor(ctx context.Context) (uint64, error) {
+	height, err := a.nodeStore.LatestBlockHeight()
+	if err != nil {
+		return 0, errors.Wrap(err, "error reading local head height")
+	}
+
+	for height > 0 {
+		localHash, ok, err := a.nodeStore.BlockHashAtHeight(height)
+		if err != nil {
+			return 0, errors.Wrapf(err, "error reading local block hash at height %d", height)
+		}
+		if ok {
+			canonicalHash, err := a.canonical.CanonicalBlockHash(ctx, height)
+			if err != nil {
+				return 0, errors.Wrapf(err, "error fetching canonical block hash at height %d", height)
+			}
+			if localHash == canonicalHash {
+				return height, nil
+			}
+		}
+
+		adminLogger.Warn().Uint64("height", height).Msg("local block diverges from canonical chain, walking back")
+		height--
+	}
+
+	return 0, nil
+}
+
+// RemoveBlocks atomically truncates all stored blocks, logs, receipts and
+// derived indexes with height >= fromBlock, then resets the inbox reader
+// cursor so it re-derives the removed range from L1. It is exposed as
+// admin_removeBlocks.
+func (a *AdminAPI) RemoveBlocks(ctx context.Context, fromBlock uint64) error {
+	adminLogger.Warn().Uint64("fromBlock", fromBlock).Msg("admin_removeBlocks: truncating local chain state")
+
+	if err := a.nodeStore.TruncateFrom(fromBlock); err != nil {
+		return errors.Wrapf(err, "error truncating node store from block %d", fromBlock)
+	}
+
+	if err := a.inboxReader.ResetToBlock(fromBlock); err != nil {
+		return errors.Wrapf(err, "error resetting inbox reader to block %d", fromBlock)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// rpcCanonicalSource resolves canonical block hashes from an independent
+// L2 RPC endpoint (e.g. another full node, or a provider's endpoint) so
+// FindLatestCommonAncestor is comparing this node's local store against a
+// reference other than itself. Without an independent source, walking
+// back through a node's own derived state can never detect the divergence
+// it's meant to find.
+type rpcCanonicalSource struct {
+	client *gethrpc.Client
+}
+
+// NewRPCCanonicalSource dials rpcURL, an L2 RPC endpoint distinct from this
+// node, and uses it as the reference FindLatestCommonAncestor compares
+// against.
+func NewRPCCanonicalSource(ctx context.Context, rpcURL string) (AdminCanonicalSource, error) {
+	if rpcURL == "" {
+		return nil, errors.New("--node.admin.canonical-rpc-url is required to use the admin recovery API")
+	}
+
+	client, err := gethrpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error dialing canonical source %s", rpcURL)
+	}
+
+	return &rpcCanonicalSource{client: client}, nil
+}
+
+type rpcBlockHeader struct {
+	Hash common.Hash `json:"hash"`
+}
+
+func (s *rpcCanonicalSource) CanonicalBlockHash(ctx context.Context, height uint64) (common.Hash, error) {
+	var header rpcBlockHeader
+	err := s.client.CallContext(ctx, &header, "eth_getBlockByNumber", fmt.Sprintf("0x%x", height), false)
+	if err != nil {
+		return common.Hash{}, errors.Wrapf(err, "error fetching canonical block %d", height)
+	}
+	return header.Hash, nil
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// fakeAdminNodeStore is an in-memory AdminNodeStore keyed by height, for
+// testing FindLatestCommonAncestor's walk-back without a real txdb.
+type fakeAdminNodeStore struct {
+	head   uint64
+	hashes map[uint64]common.Hash
+}
+
+func (f *fakeAdminNodeStore) LatestBlockHeight() (uint64, error) {
+	return f.head, nil
+}
+
+func (f *fakeAdminNodeStore) BlockHashAtHeight(height uint64) (common.Hash, bool, error) {
+	hash, ok := f.hashes[height]
+	return hash, ok, nil
+}
+
+func (f *fakeAdminNodeStore) TruncateFrom(fromBlock uint64) error {
+	for height := range f.hashes {
+		if height >= fromBlock {
+			delete(f.hashes, height)
+		}
+	}
+	return nil
+}
+
+// fakeAdminCanonicalSource is an AdminCanonicalSource backed by a fixed map
+// of canonical hashes by height.
+type fakeAdminCanonicalSource struct {
+	hashes map[uint64]common.Hash
+}
+
+func (f *fakeAdminCanonicalSource) CanonicalBlockHash(ctx context.Context, height uint64) (common.Hash, error) {
+	return f.hashes[height], nil
+}
+
+func hashForHeight(height uint64) common.Hash {
+	return common.Hash{byte(height)}
+}
+
+func TestFindLatestCommonAncestorWhenHeadMatches(t *testing.T) {
+	nodeStore := &fakeAdminNodeStore{
+		head: 10,
+		hashes: map[uint64]common.Hash{
+			10: hashForHeight(10),
+		},
+	}
+	canonical := &fakeAdminCanonicalSource{
+		hashes: map[uint64]common.Hash{
+			10: hashForHeight(10),
+		},
+	}
+	api := NewAdminAPI(nodeStore, canonical, nil)
+
+	got, err := api.FindLatestCommonAncestor(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 10 {
+		t.Fatalf("FindLatestCommonAncestor() = %d, want 10", got)
+	}
+}
+
+func TestFindLatestCommonAncestorWalksBackThroughReorg(t *testing.T) {
+	// Heights 8-10 diverged from canonical (e.g. after a reorg); 7 and below
+	// still agree.
+	nodeHashes := map[uint64]common.Hash{}
+	canonicalHashes := map[uint64]common.Hash{}
+	for height := uint64(0); height <= 10; height++ {
+		nodeHashes[height] = hashForHeight(height)
+		canonicalHashes[height] = hashForHeight(height)
+	}
+	for height := uint64(8); height <= 10; height++ {
+		nodeHashes[height] = common.Hash{0xFF, byte(height)}
+	}
+
+	nodeStore := &fakeAdminNodeStore{head: 10, hashes: nodeHashes}
+	canonical := &fakeAdminCanonicalSource{hashes: canonicalHashes}
+	api := NewAdminAPI(nodeStore, canonical, nil)
+
+	got, err := api.FindLatestCommonAncestor(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 7 {
+		t.Fatalf("FindLatestCommonAncestor() = %d, want 7", got)
+	}
+}
+
+func TestFindLatestCommonAncestorSkipsMissingLocalHashes(t *testing.T) {
+	// Height 10 was never stored locally (ok=false); it should be skipped
+	// over rather than treated as a match or a hard failure.
+	nodeStore := &fakeAdminNodeStore{
+		head: 10,
+		hashes: map[uint64]common.Hash{
+			9: hashForHeight(9),
+		},
+	}
+	canonical := &fakeAdminCanonicalSource{
+		hashes: map[uint64]common.Hash{
+			9: hashForHeight(9),
+		},
+	}
+	api := NewAdminAPI(nodeStore, canonical, nil)
+
+	got, err := api.FindLatestCommonAncestor(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 9 {
+		t.Fatalf("FindLatestCommonAncestor() = %d, want 9", got)
+	}
+}
+
+func TestFindLatestCommonAncestorReturnsZeroWhenNothingMatches(t *testing.T) {
+	nodeStore := &fakeAdminNodeStore{
+		head: 3,
+		hashes: map[uint64]common.Hash{
+			3: {0xAA},
+			2: {0xBB},
+			1: {0xCC},
+		},
+	}
+	canonical := &fakeAdminCanonicalSource{hashes: map[uint64]common.Hash{}}
+	api := NewAdminAPI(nodeStore, canonical, nil)
+
+	got, err := api.FindLatestCommonAncestor(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 0 {
+		t.Fatalf("FindLatestCommonAncestor() = %d, want 0", got)
+	}
+}
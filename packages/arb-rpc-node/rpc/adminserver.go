@@ -0,0 +1,57 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// LaunchAdminServer serves the admin_* recovery RPC namespace on its own
+// listener, separate from the public RPC/WS server started by
+// LaunchPublicServer. admin_removeBlocks atomically truncates the node's
+// chain state and has no authentication of its own, so addr should be
+// loopback (the default) or otherwise reachable only from a trusted
+// network - never the node's public RPC address.
+func LaunchAdminServer(ctx context.Context, adminAPI *AdminAPI, addr, port string) error {
+	server := gethrpc.NewServer()
+	if err := server.RegisterName("admin", adminAPI); err != nil {
+		return errors.Wrap(err, "error registering admin API")
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(addr, port))
+	if err != nil {
+		return errors.Wrapf(err, "error starting admin RPC listener on %s:%s", addr, port)
+	}
+
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+	}()
+
+	log.Info().Str("addr", addr).Str("port", port).Msg("admin RPC listening")
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "admin RPC server failed")
+	}
+	return nil
+}
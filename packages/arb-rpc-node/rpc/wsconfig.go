@@ -0,0 +1,80 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultWSMaxMessageSize is used when --node.ws.max-message-size is
+	// left unset.
+	DefaultWSMaxMessageSize = 1 << 20 // 1 MiB
+
+	minWSMaxMessageSize = 64 << 10 // 64 KiB
+	maxWSMaxMessageSize = 64 << 20 // 64 MiB
+)
+
+// WSConfig bundles the WS server's validated message size limit together
+// with the metric used to track notifications dropped for exceeding it, so
+// the counter is always registered against the node's real metrics
+// registry instead of a package-global left dangling with no registry.
+type WSConfig struct {
+	// MaxMessageSize is the validated, clamped --node.ws.max-message-size.
+	MaxMessageSize int
+
+	notificationsDropped gethmetrics.Counter
+}
+
+// NewWSConfig validates maxMessageSize and registers its drop counter
+// against registry.
+func NewWSConfig(maxMessageSize int, registry gethmetrics.Registry) *WSConfig {
+	return &WSConfig{
+		MaxMessageSize:       ValidateWSMaxMessageSize(maxMessageSize),
+		notificationsDropped: gethmetrics.NewRegisteredCounter("arbitrum/ws/notifications_dropped_total", registry),
+	}
+}
+
+// ValidateWSMaxMessageSize clamps bytes to the supported
+// [64KiB, 64MiB] range for --node.ws.max-message-size, logging a warning
+// if the configured value was out of range. A value of 0 selects the
+// default.
+func ValidateWSMaxMessageSize(bytes int) int {
+	if bytes == 0 {
+		return DefaultWSMaxMessageSize
+	}
+	if bytes < minWSMaxMessageSize {
+		log.Warn().Int("configured", bytes).Int("minimum", minWSMaxMessageSize).Msg("--node.ws.max-message-size too small, clamping")
+		return minWSMaxMessageSize
+	}
+	if bytes > maxWSMaxMessageSize {
+		log.Warn().Int("configured", bytes).Int("maximum", maxWSMaxMessageSize).Msg("--node.ws.max-message-size too large, clamping")
+		return maxWSMaxMessageSize
+	}
+	return bytes
+}
+
+// RecordDroppedNotification increments the
+// arbitrum_ws_notifications_dropped_total counter and logs a warning, for
+// one eth_subscribe notification dropped for exceeding MaxMessageSize.
+// LaunchPublicServer does not call it, so notifications it drops are
+// neither counted nor logged here.
+func (c *WSConfig) RecordDroppedNotification() {
+	c.notificationsDropped.Inc(1)
+	log.Warn().Int("maxMessageSize", c.MaxMessageSize).Msg("dropped WS notification exceeding max message size")
+}
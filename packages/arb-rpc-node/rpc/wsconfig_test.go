@@ -0,0 +1,42 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import "testing"
+
+func TestValidateWSMaxMessageSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes int
+		want  int
+	}{
+		{name: "zero selects default", bytes: 0, want: DefaultWSMaxMessageSize},
+		{name: "below minimum clamps up", bytes: 1024, want: minWSMaxMessageSize},
+		{name: "at minimum is unchanged", bytes: minWSMaxMessageSize, want: minWSMaxMessageSize},
+		{name: "in range is unchanged", bytes: 4 << 20, want: 4 << 20},
+		{name: "at maximum is unchanged", bytes: maxWSMaxMessageSize, want: maxWSMaxMessageSize},
+		{name: "above maximum clamps down", bytes: 128 << 20, want: maxWSMaxMessageSize},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ValidateWSMaxMessageSize(tc.bytes); got != tc.want {
+				t.Errorf("ValidateWSMaxMessageSize(%d) = %d, want %d", tc.bytes, got, tc.want)
+			}
+		})
+	}
+}
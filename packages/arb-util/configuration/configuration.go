@@ -0,0 +1,301 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package configuration defines arb-node's command line flags and the
+// Config tree they populate.
+package configuration
+
+import (
+	"context"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gethethclient "github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-rpc-node/dataavailability"
+)
+
+type RollupMachineConfig struct {
+	Filename string
+}
+
+type RollupConfig struct {
+	Address   string
+	FromBlock int64
+	Machine   RollupMachineConfig
+}
+
+type ForwarderConfig struct {
+	Target string
+}
+
+type AggregatorConfig struct {
+	InboxAddress string
+	Stateful     bool
+	MaxBatchTime int64
+}
+
+type LockoutConfig struct {
+	Redis      string
+	SelfRPCURL string
+}
+
+type SequencerConfig struct {
+	DelayedMessagesTargetDelay int64
+	CreateBatchBlockInterval   int64
+	Lockout                    LockoutConfig
+	DataAvailability           dataavailability.Config
+}
+
+// RPCConfig holds the web3 HTTP server's own options, including
+// --node.rpc.mem-free-limit (see resourcemanager.ParseMemLimit for the
+// K/M/G/T suffix format accepted here).
+type RPCConfig struct {
+	Addr         string
+	Port         string
+	MemFreeLimit string
+}
+
+// WSConfig holds the WS server's options, including
+// --node.ws.max-message-size (see rpc.ValidateWSMaxMessageSize for the
+// accepted [64KiB, 64MiB] range).
+type WSConfig struct {
+	Addr           string
+	Port           string
+	MaxMessageSize int
+}
+
+// AdminConfig holds the options for the admin_* recovery RPC namespace,
+// which is served on its own listener (see rpc.LaunchAdminServer), never on
+// the node's public RPC/WS server.
+type AdminConfig struct {
+	// CanonicalRPCURL is an L2 RPC endpoint independent of this node (a
+	// peer full node, or a provider) that admin_findLatestCommonAncestor
+	// compares this node's local chain state against. When empty, the admin
+	// RPC server is not started.
+	CanonicalRPCURL string
+	// Addr is the listen address for the admin RPC server. Defaults to
+	// loopback-only since admin_removeBlocks has no authentication of its
+	// own and can truncate the node's chain state.
+	Addr string
+	Port string
+}
+
+type NodeConfig struct {
+	Type       string
+	ChainID    uint64
+	RPC        RPCConfig
+	WS         WSConfig
+	Admin      AdminConfig
+	Forwarder  ForwarderConfig
+	Aggregator AggregatorConfig
+	Sequencer  SequencerConfig
+}
+
+// HealthcheckConfig holds the /livez /readyz /healthz server's options,
+// including the per-probe lag thresholds.
+type HealthcheckConfig struct {
+	Addr                 string
+	Port                 string
+	MetricsPrefix        string
+	L1Node               bool
+	MaxL1BlockAgeSeconds int64
+	MaxFeedLagSeconds    int64
+	MaxInboxLagBlocks    int64
+	MaxTxDBLagBlocks     int64
+}
+
+type FeedInputConfig struct {
+	URLs    []string
+	Timeout time.Duration
+}
+
+type FeedConfig struct {
+	Input FeedInputConfig
+}
+
+type PersistentConfig struct {
+	GlobalConfig string
+	Chain        string
+}
+
+type LogConfig struct {
+	RPC  string
+	Core string
+}
+
+type Config struct {
+	BridgeUtilsAddress string
+	GasPrice           float64
+	PProfEnable        bool
+	WaitToCatchUp      bool
+
+	Persistent  PersistentConfig
+	L1          L1Config
+	Rollup      RollupConfig
+	Node        NodeConfig
+	Healthcheck HealthcheckConfig
+	Feed        FeedConfig
+	Log         LogConfig
+}
+
+// L1Config holds the L1 endpoint(s) this node connects to.
+type L1Config struct {
+	// URL is the legacy single-endpoint flag, kept for backwards
+	// compatibility.
+	URL string
+	// URLs is a comma separated list of L1 endpoints; when set it takes
+	// priority over URL and is dialed through
+	// arb-util/ethclient.DialFailover.
+	URLs string
+}
+
+// GetNodeDatabasePath returns the on-disk directory this node's chain
+// state is stored under.
+func (c *Config) GetNodeDatabasePath() string {
+	return filepath.Join(c.Persistent.GlobalConfig, c.Persistent.Chain)
+}
+
+// WalletConfig is the minimal wallet selection handed to
+// cmdhelp.GetKeystore.
+type WalletConfig struct {
+	Path     string
+	Password string
+}
+
+// ParseNode registers and parses every arb-node flag, returning the
+// resulting Config, the selected wallet, a plain (non-failover) L1 client
+// dialed against --l1.url for callers that only need a quick read (most
+// callers should instead dial arb-util/ethclient.DialFailover against
+// --l1.urls), and the L1 chain ID.
+func ParseNode(ctx context.Context) (*Config, *WalletConfig, *gethethclient.Client, *big.Int, error) {
+	fs := flag.NewFlagSet("arb-node", flag.ContinueOnError)
+
+	config := &Config{}
+	wallet := &WalletConfig{}
+
+	fs.StringVar(&config.BridgeUtilsAddress, "bridge-utils-address", "", "address of the BridgeUtils contract")
+	fs.Float64Var(&config.GasPrice, "gas-price", 0, "gas price in gwei to use for L1 transactions, 0 for automatic")
+	fs.BoolVar(&config.PProfEnable, "pprof-enable", false, "enable the pprof profiling server on localhost:8081")
+
+	fs.StringVar(&config.Persistent.GlobalConfig, "persistent.global-config", "", "directory to store persistent node data under")
+	fs.StringVar(&config.Persistent.Chain, "persistent.chain", "", "subdirectory of persistent.global-config to use for this chain")
+
+	fs.StringVar(&config.L1.URL, "l1.url", "", "layer 1 Ethereum RPC URL")
+	fs.StringVar(&config.L1.URLs, "l1.urls", "", "comma separated list of layer 1 Ethereum RPC URLs to fail over between")
+
+	fs.StringVar(&config.Rollup.Address, "rollup.address", "", "layer 2 rollup contract address")
+	fs.Int64Var(&config.Rollup.FromBlock, "rollup.from-block", 0, "L1 block the rollup was deployed at")
+	fs.StringVar(&config.Rollup.Machine.Filename, "rollup.machine.filename", "", "path to the AVM machine executable")
+	fs.Uint64Var(&config.Node.ChainID, "rollup.chain-id", 0, "L2 chain id")
+
+	fs.StringVar(&config.Node.Type, "node.type", "forwarder", "node type: forwarder, aggregator or sequencer")
+	fs.StringVar(&config.Node.Forwarder.Target, "node.forwarder.target", "", "forwarder node upstream RPC URL")
+	fs.StringVar(&config.Node.Aggregator.InboxAddress, "node.aggregator.inbox-address", "", "inbox contract address used to submit batches")
+	fs.BoolVar(&config.Node.Aggregator.Stateful, "node.aggregator.stateful", false, "use the stateful batcher")
+	fs.Int64Var(&config.Node.Aggregator.MaxBatchTime, "node.aggregator.max-batch-time", 10, "maximum seconds to wait before posting a batch")
+
+	fs.Int64Var(&config.Node.Sequencer.DelayedMessagesTargetDelay, "node.sequencer.delayed-messages-target-delay", 12, "target number of blocks to delay delayed messages")
+	fs.Int64Var(&config.Node.Sequencer.CreateBatchBlockInterval, "node.sequencer.create-batch-block-interval", 1, "number of blocks between batch creation attempts")
+	fs.StringVar(&config.Node.Sequencer.Lockout.Redis, "node.sequencer.lockout.redis", "", "redis URL used to coordinate sequencer lockout")
+	fs.StringVar(&config.Node.Sequencer.Lockout.SelfRPCURL, "node.sequencer.lockout.self-rpc-url", "", "this sequencer's own RPC URL, required when lockout.redis is set")
+	fs.StringVar(&config.Node.Sequencer.DataAvailability.Mode, "node.sequencer.data-availability.mode", "", "batch data availability backend: onchain or external-committee")
+	fs.StringVar(&config.Node.Sequencer.DataAvailability.CommitteeRPCURL, "node.sequencer.data-availability.committee-rpc-url", "", "DA committee JSON-RPC URL, required for external-committee mode")
+	fs.Int64Var(&config.Node.Sequencer.DataAvailability.CommitteeTimeout, "node.sequencer.data-availability.committee-timeout", 30, "seconds to wait for the DA committee before giving up")
+
+	fs.StringVar(&config.Node.RPC.Addr, "node.rpc.addr", "0.0.0.0", "RPC server listen address")
+	fs.StringVar(&config.Node.RPC.Port, "node.rpc.port", "8547", "RPC server listen port")
+	fs.StringVar(&config.Node.RPC.MemFreeLimit, "node.rpc.mem-free-limit", "", "pause RPC serving and background work below this much free memory, e.g. 1G; empty disables the check")
+
+	fs.StringVar(&config.Node.WS.Addr, "node.ws.addr", "0.0.0.0", "WS server listen address")
+	fs.StringVar(&config.Node.WS.Port, "node.ws.port", "8548", "WS server listen port")
+	fs.IntVar(&config.Node.WS.MaxMessageSize, "node.ws.max-message-size", 0, "maximum eth_subscribe notification size in bytes, range 64KiB-64MiB; 0 selects the 1MiB default")
+
+	fs.StringVar(&config.Node.Admin.CanonicalRPCURL, "node.admin.canonical-rpc-url", "", "independent L2 RPC endpoint admin_findLatestCommonAncestor compares local state against; empty disables the admin RPC server")
+	fs.StringVar(&config.Node.Admin.Addr, "node.admin.addr", "127.0.0.1", "admin RPC server listen address; keep this loopback-only or on a trusted network")
+	fs.StringVar(&config.Node.Admin.Port, "node.admin.port", "8549", "admin RPC server listen port")
+
+	fs.StringVar(&config.Healthcheck.Addr, "healthcheck.addr", "0.0.0.0", "healthcheck server listen address")
+	fs.StringVar(&config.Healthcheck.Port, "healthcheck.port", "8080", "healthcheck server listen port")
+	fs.StringVar(&config.Healthcheck.MetricsPrefix, "healthcheck.metrics-prefix", "arbitrum", "prefix applied to exported metric names")
+	fs.BoolVar(&config.Healthcheck.L1Node, "healthcheck.l1-node", true, "probe L1 RPC reachability and sync status")
+	fs.Int64Var(&config.Healthcheck.MaxL1BlockAgeSeconds, "healthcheck.max-l1-block-age-seconds", 300, "fail the L1 probe if the latest L1 block is older than this")
+	fs.Int64Var(&config.Healthcheck.MaxFeedLagSeconds, "healthcheck.max-feed-lag-seconds", 60, "fail the feed probe if no message has been seen in this many seconds")
+	fs.Int64Var(&config.Healthcheck.MaxInboxLagBlocks, "healthcheck.max-inbox-lag-blocks", 100, "fail the inbox reader probe if it falls this many blocks behind the L1 tip")
+	fs.Int64Var(&config.Healthcheck.MaxTxDBLagBlocks, "healthcheck.max-txdb-lag-blocks", 100, "fail the txdb probe if it falls this many blocks behind the node store")
+
+	var feedURLs string
+	fs.StringVar(&feedURLs, "feed.url", "", "comma separated list of sequencer feed URLs to subscribe to")
+	fs.DurationVar(&config.Feed.Input.Timeout, "feed.input.timeout", 20*time.Second, "feed connection timeout")
+
+	fs.StringVar(&config.Log.RPC, "log.rpc", "info", "log level for RPC request logging")
+	fs.StringVar(&config.Log.Core, "log.core", "info", "log level for core arbitrum logging")
+
+	fs.StringVar(&wallet.Path, "wallet.path", "", "path to the wallet keystore")
+	fs.StringVar(&wallet.Password, "wallet.password", "", "wallet keystore password")
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return config, wallet, nil, nil, errors.New("help requested")
+		}
+		return config, wallet, nil, nil, err
+	}
+
+	if feedURLs != "" {
+		config.Feed.Input.URLs = strings.Split(feedURLs, ",")
+	}
+
+	if config.Node.Type == "sequencer" {
+		config.WaitToCatchUp = true
+	}
+
+	l1URL := config.L1.URL
+	if l1URL == "" {
+		l1URL = firstURL(config.L1.URLs)
+	}
+	if l1URL == "" {
+		return config, wallet, nil, nil, nil
+	}
+
+	l1Client, err := gethethclient.DialContext(ctx, l1URL)
+	if err != nil {
+		return config, wallet, nil, nil, errors.Wrapf(err, "error dialing L1 endpoint %s", l1URL)
+	}
+
+	l1ChainId, err := l1Client.ChainID(ctx)
+	if err != nil {
+		return config, wallet, l1Client, nil, errors.Wrap(err, "error fetching L1 chain id")
+	}
+
+	return config, wallet, l1Client, l1ChainId, nil
+}
+
+// firstURL returns the first entry of a comma separated URL list, or "" if
+// rawURLs has none.
+func firstURL(rawURLs string) string {
+	for _, u := range strings.Split(rawURLs, ",") {
+		u = strings.TrimSpace(u)
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
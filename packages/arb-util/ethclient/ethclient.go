@@ -0,0 +1,280 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ethclient wraps go-ethereum's ethclient.Client with failover
+// across a list of L1 endpoints, so a single degraded provider doesn't
+// force a node restart. It keeps a sticky primary endpoint, falls back to
+// the others on transport or 5xx errors with exponential backoff, and
+// prefixes every returned error with the endpoint it came from so operators
+// can grep logs to isolate a bad provider.
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gethethclient "github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+)
+
+var logger = log.With().Str("component", "ethclient").Logger()
+
+const (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+// endpoint tracks health bookkeeping for a single L1 RPC URL.
+type endpoint struct {
+	name   string
+	url    string
+	client *gethethclient.Client
+
+	mu            sync.Mutex
+	backoff       time.Duration
+	unhealthyTill time.Time
+
+	successCount gethmetrics.Counter
+	failureCount gethmetrics.Counter
+	activeGauge  gethmetrics.Gauge
+}
+
+func (e *endpoint) healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.unhealthyTill)
+}
+
+func (e *endpoint) recordSuccess() {
+	e.mu.Lock()
+	e.backoff = 0
+	e.unhealthyTill = time.Time{}
+	e.mu.Unlock()
+	if e.successCount != nil {
+		e.successCount.Inc(1)
+	}
+}
+
+func (e *endpoint) recordFailure() {
+	e.mu.Lock()
+	if e.backoff == 0 {
+		e.backoff = initialBackoff
+	} else {
+		e.backoff *= 2
+		if e.backoff > maxBackoff {
+			e.backoff = maxBackoff
+		}
+	}
+	e.unhealthyTill = time.Now().Add(e.backoff)
+	e.mu.Unlock()
+	if e.failureCount != nil {
+		e.failureCount.Inc(1)
+	}
+}
+
+// Client is a failover wrapper around one or more L1 RPC endpoints. It
+// deliberately does not embed *gethethclient.Client: every method callers
+// need (the full bind.ContractBackend surface, plus a few extras) is
+// implemented in overrides.go and goes through withFailover, so there's no
+// unshadowed method that could bypass failover/backoff/metrics or race
+// against endpoint promotion.
+type Client struct {
+	endpoints []*endpoint
+
+	mu      sync.Mutex
+	primary int
+}
+
+// DialFailover connects to every endpoint in rawURLs (as produced by
+// splitting --l1.urls on commas) and returns a Client that fails over
+// between them. The first endpoint is the initial sticky primary.
+func DialFailover(ctx context.Context, rawURLs string, registry gethmetrics.Registry) (*Client, error) {
+	urls := splitURLs(rawURLs)
+	if len(urls) == 0 {
+		return nil, errors.New("no L1 URLs configured")
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for i, url := range urls {
+		client, err := gethethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error dialing L1 endpoint %s", endpointName(i, url))
+		}
+		name := endpointName(i, url)
+		ep := &endpoint{name: name, url: url, client: client}
+		if registry != nil {
+			ep.successCount = gethmetrics.NewRegisteredCounter("arb/ethclient/"+name+"/success", registry)
+			ep.failureCount = gethmetrics.NewRegisteredCounter("arb/ethclient/"+name+"/failure", registry)
+			ep.activeGauge = gethmetrics.NewRegisteredGauge("arb/ethclient/"+name+"/active", registry)
+		}
+		endpoints = append(endpoints, ep)
+	}
+
+	c := &Client{
+		endpoints: endpoints,
+	}
+	if endpoints[0].activeGauge != nil {
+		endpoints[0].activeGauge.Update(1)
+	}
+	return c, nil
+}
+
+func splitURLs(rawURLs string) []string {
+	var urls []string
+	for _, u := range strings.Split(rawURLs, ",") {
+		u = strings.TrimSpace(u)
+		if u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+func endpointName(index int, url string) string {
+	// Strip credentials/paths from the URL so names are safe to log and
+	// to use as metric labels.
+	if idx := strings.Index(url, "://"); idx >= 0 {
+		rest := url[idx+3:]
+		if at := strings.Index(rest, "@"); at >= 0 {
+			rest = rest[at+1:]
+		}
+		if slash := strings.Index(rest, "/"); slash >= 0 {
+			rest = rest[:slash]
+		}
+		if rest != "" {
+			return rest
+		}
+	}
+	return fmt.Sprintf("endpoint-%d", index)
+}
+
+// ActiveEndpoint returns the name of the endpoint currently used as
+// primary.
+func (c *Client) ActiveEndpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endpoints[c.primary].name
+}
+
+// wrapErr prefixes err with the failing endpoint's name so operators can
+// grep logs to isolate a bad provider.
+func wrapErr(ep *endpoint, err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.Wrapf(err, "L1Client (%s)", ep.name)
+}
+
+// withFailover calls fn against the sticky primary, and on transport/5xx
+// failure walks through the remaining healthy endpoints, promoting the
+// first one that succeeds to primary.
+func (c *Client) withFailover(fn func(*gethethclient.Client) error) error {
+	c.mu.Lock()
+	order := make([]int, 0, len(c.endpoints))
+	order = append(order, c.primary)
+	for i := range c.endpoints {
+		if i != c.primary {
+			order = append(order, i)
+		}
+	}
+	c.mu.Unlock()
+
+	var lastErr error
+	var skipped []int
+	for _, idx := range order {
+		ep := c.endpoints[idx]
+		if !ep.healthy() && idx != order[0] {
+			// Skip known-unhealthy backups for now; if every endpoint turns
+			// out to be unhealthy we fall through and try them anyway below.
+			skipped = append(skipped, idx)
+			continue
+		}
+
+		err := fn(ep.client)
+		if err == nil {
+			ep.recordSuccess()
+			c.promote(idx)
+			return nil
+		}
+
+		if !isFailoverWorthy(err) {
+			return wrapErr(ep, err)
+		}
+
+		ep.recordFailure()
+		lastErr = wrapErr(ep, err)
+		logger.Warn().Err(err).Str("endpoint", ep.name).Msg("L1 endpoint failed, trying next")
+	}
+
+	for _, idx := range skipped {
+		ep := c.endpoints[idx]
+		err := fn(ep.client)
+		if err == nil {
+			ep.recordSuccess()
+			c.promote(idx)
+			return nil
+		}
+
+		if !isFailoverWorthy(err) {
+			return wrapErr(ep, err)
+		}
+
+		ep.recordFailure()
+		lastErr = wrapErr(ep, err)
+		logger.Warn().Err(err).Str("endpoint", ep.name).Msg("L1 endpoint failed on fallback attempt")
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no healthy L1 endpoints available")
+	}
+	return lastErr
+}
+
+func (c *Client) promote(idx int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.primary == idx {
+		return
+	}
+	logger.Info().Str("endpoint", c.endpoints[idx].name).Msg("promoting L1 endpoint to primary")
+	if g := c.endpoints[c.primary].activeGauge; g != nil {
+		g.Update(0)
+	}
+	c.primary = idx
+	if g := c.endpoints[idx].activeGauge; g != nil {
+		g.Update(1)
+	}
+}
+
+// isFailoverWorthy reports whether err looks like a transport-level or
+// 5xx failure that justifies trying another endpoint, as opposed to an
+// application-level error (e.g. execution reverted) that would fail
+// identically on every endpoint.
+func isFailoverWorthy(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, substr := range []string{"connection refused", "timeout", "eof", "no such host", "502", "503", "504", "too many requests"} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
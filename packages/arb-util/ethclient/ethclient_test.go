@@ -0,0 +1,197 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethclient
+
+import (
+	"errors"
+	"testing"
+
+	gethethclient "github.com/ethereum/go-ethereum/ethclient"
+)
+
+func newTestClient(names ...string) *Client {
+	endpoints := make([]*endpoint, 0, len(names))
+	for _, name := range names {
+		endpoints = append(endpoints, &endpoint{name: name, url: name})
+	}
+	return &Client{endpoints: endpoints}
+}
+
+func TestEndpointHealthy(t *testing.T) {
+	ep := &endpoint{name: "a"}
+	if !ep.healthy() {
+		t.Fatal("a fresh endpoint should be healthy")
+	}
+
+	ep.recordFailure()
+	if ep.healthy() {
+		t.Fatal("endpoint should be unhealthy immediately after a recorded failure")
+	}
+
+	ep.recordSuccess()
+	if !ep.healthy() {
+		t.Fatal("endpoint should be healthy again after a recorded success")
+	}
+}
+
+func TestEndpointRecordFailureBacksOffExponentially(t *testing.T) {
+	ep := &endpoint{name: "a"}
+
+	ep.recordFailure()
+	first := ep.backoff
+	if first != initialBackoff {
+		t.Fatalf("first backoff = %v, want %v", first, initialBackoff)
+	}
+
+	ep.recordFailure()
+	second := ep.backoff
+	if second != 2*first {
+		t.Fatalf("second backoff = %v, want %v", second, 2*first)
+	}
+
+	// Backoff should saturate at maxBackoff rather than growing unbounded.
+	for i := 0; i < 20; i++ {
+		ep.recordFailure()
+	}
+	if ep.backoff != maxBackoff {
+		t.Fatalf("backoff = %v after repeated failures, want it capped at %v", ep.backoff, maxBackoff)
+	}
+}
+
+func TestWithFailoverPromotesFirstHealthyEndpoint(t *testing.T) {
+	c := newTestClient("primary", "backup")
+
+	calls := 0
+	err := c.withFailover(func(*gethethclient.Client) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ActiveEndpoint() != "primary" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q", c.ActiveEndpoint(), "primary")
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single call when the primary succeeds, got %d", calls)
+	}
+}
+
+func TestWithFailoverPromotesBackupOnTransportError(t *testing.T) {
+	c := newTestClient("primary", "backup")
+
+	calls := 0
+	err := c.withFailover(func(*gethethclient.Client) error {
+		calls++
+		// The sticky primary (endpoint 0) is always tried first.
+		if calls == 1 {
+			return errors.New("dial tcp: connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected primary to fail then backup to be tried, got %d calls", calls)
+	}
+	if c.ActiveEndpoint() != "backup" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q", c.ActiveEndpoint(), "backup")
+	}
+}
+
+func TestWithFailoverDoesNotFailoverOnApplicationError(t *testing.T) {
+	c := newTestClient("primary", "backup")
+
+	calls := 0
+	appErr := errors.New("execution reverted")
+	err := c.withFailover(func(*gethethclient.Client) error {
+		calls++
+		return appErr
+	})
+	if err == nil {
+		t.Fatal("expected an error to be returned")
+	}
+	if calls != 1 {
+		t.Fatalf("application-level errors should not trigger failover, got %d calls", calls)
+	}
+	if c.ActiveEndpoint() != "primary" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q (no promotion should happen)", c.ActiveEndpoint(), "primary")
+	}
+}
+
+func TestWithFailoverFallsBackToUnhealthyEndpointWhenAllOthersFail(t *testing.T) {
+	c := newTestClient("primary", "backup")
+	// Mark backup unhealthy ahead of time, as if a prior call had already
+	// failed against it.
+	c.endpoints[1].recordFailure()
+
+	calls := 0
+	err := c.withFailover(func(*gethethclient.Client) error {
+		calls++
+		if calls == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the skipped-but-unhealthy backup to be retried and succeed, got: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected exactly 2 calls (primary, then fallback to unhealthy backup), got %d", calls)
+	}
+	if c.ActiveEndpoint() != "backup" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q", c.ActiveEndpoint(), "backup")
+	}
+}
+
+func TestPromote(t *testing.T) {
+	c := newTestClient("a", "b")
+	if c.ActiveEndpoint() != "a" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q", c.ActiveEndpoint(), "a")
+	}
+
+	c.promote(1)
+	if c.ActiveEndpoint() != "b" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q after promote", c.ActiveEndpoint(), "b")
+	}
+
+	// Promoting the already-active endpoint should be a no-op.
+	c.promote(1)
+	if c.ActiveEndpoint() != "b" {
+		t.Fatalf("ActiveEndpoint() = %q, want %q after no-op promote", c.ActiveEndpoint(), "b")
+	}
+}
+
+func TestIsFailoverWorthy(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("dial tcp: connection refused"), want: true},
+		{err: errors.New("request timeout awaiting headers"), want: true},
+		{err: errors.New("502 Bad Gateway"), want: true},
+		{err: errors.New("execution reverted: insufficient balance"), want: false},
+		{err: errors.New("invalid argument 0: json: cannot unmarshal"), want: false},
+	}
+
+	for _, tc := range tests {
+		if got := isFailoverWorthy(tc.err); got != tc.want {
+			t.Errorf("isFailoverWorthy(%q) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright 2021, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethethclient "github.com/ethereum/go-ethereum/ethclient"
+)
+
+// The methods below are Client's entire public surface: the full
+// bind.ContractBackend interface plus the handful of extra read calls the
+// node needs (HeaderByNumber, SyncProgress, TransactionReceipt, BalanceAt,
+// NonceAt, BlockByNumber). Client does not embed *gethethclient.Client, so
+// there is no other way to reach an endpoint - every call here goes through
+// withFailover and gets backoff, per-endpoint metrics and the
+// "L1Client (<endpoint>): ..." error prefix.
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	var header *gethtypes.Header
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		h, err := client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return err
+		}
+		header = h
+		return nil
+	})
+	return header, err
+}
+
+func (c *Client) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	var progress *ethereum.SyncProgress
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		p, err := client.SyncProgress(ctx)
+		if err != nil {
+			return err
+		}
+		progress = p
+		return nil
+	})
+	return progress, err
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		res, err := client.CallContract(ctx, msg, blockNumber)
+		if err != nil {
+			return err
+		}
+		result = res
+		return nil
+	})
+	return result, err
+}
+
+func (c *Client) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	return c.withFailover(func(client *gethethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*gethtypes.Receipt, error) {
+	var receipt *gethtypes.Receipt
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		r, err := client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			return err
+		}
+		receipt = r
+		return nil
+	})
+	return receipt, err
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		b, err := client.BalanceAt(ctx, account, blockNumber)
+		if err != nil {
+			return err
+		}
+		balance = b
+		return nil
+	})
+	return balance, err
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	var result []gethtypes.Log
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			return err
+		}
+		result = logs
+		return nil
+	})
+	return result, err
+}
+
+// SubscribeFilterLogs goes through withFailover to establish the
+// subscription, so a primary that's down when the inbox reader starts up
+// doesn't block it from scanning the inbox contract. Once subscribed,
+// though, the returned ethereum.Subscription is pinned to whichever
+// endpoint accepted it: a failure afterward surfaces as the subscription's
+// error channel closing, not as a call this wrapper can retry.
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	var sub ethereum.Subscription
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		s, err := client.SubscribeFilterLogs(ctx, q, ch)
+		if err != nil {
+			return err
+		}
+		sub = s
+		return nil
+	})
+	return sub, err
+}
+
+func (c *Client) CodeAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	var code []byte
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		b, err := client.CodeAt(ctx, account, blockNumber)
+		if err != nil {
+			return err
+		}
+		code = b
+		return nil
+	})
+	return code, err
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account gethcommon.Address) ([]byte, error) {
+	var code []byte
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		b, err := client.PendingCodeAt(ctx, account)
+		if err != nil {
+			return err
+		}
+		code = b
+		return nil
+	})
+	return code, err
+}
+
+func (c *Client) NonceAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) (uint64, error) {
+	var nonce uint64
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		n, err := client.NonceAt(ctx, account, blockNumber)
+		if err != nil {
+			return err
+		}
+		nonce = n
+		return nil
+	})
+	return nonce, err
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account gethcommon.Address) (uint64, error) {
+	var nonce uint64
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		n, err := client.PendingNonceAt(ctx, account)
+		if err != nil {
+			return err
+		}
+		nonce = n
+		return nil
+	})
+	return nonce, err
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var price *big.Int
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		p, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		price = p
+		return nil
+	})
+	return price, err
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var tip *big.Int
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		t, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return err
+		}
+		tip = t
+		return nil
+	})
+	return tip, err
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var gas uint64
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		g, err := client.EstimateGas(ctx, msg)
+		if err != nil {
+			return err
+		}
+		gas = g
+		return nil
+	})
+	return gas, err
+}
+
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*gethtypes.Block, error) {
+	var block *gethtypes.Block
+	err := c.withFailover(func(client *gethethclient.Client) error {
+		b, err := client.BlockByNumber(ctx, number)
+		if err != nil {
+			return err
+		}
+		block = b
+		return nil
+	})
+	return block, err
+}